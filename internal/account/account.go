@@ -0,0 +1,116 @@
+// Package account은 계정 설정 로딩과 AssumeRole/SSO 기반 AWS Config 구성을 담당한다.
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// maxRetryAttempts는 throttling 등 일시적 오류에 대해 재시도할 최대 횟수
+const maxRetryAttempts = 5
+
+// Config는 accounts.json 한 항목의 설정 구조
+type Config struct {
+	AccountID   string   `json:"account_id"`
+	RoleArn     string   `json:"role_arn"`
+	Regions     []string `json:"regions,omitempty"`
+	Profile     string   `json:"profile,omitempty"`      // AWS SSO 세션 또는 ~/.aws/credentials의 named profile
+	ExternalID  string   `json:"external_id,omitempty"`  // AssumeRole 시 전달할 ExternalId
+	MfaSerial   string   `json:"mfa_serial,omitempty"`   // MFA 기기/가상 MFA의 ARN
+	SessionName string   `json:"session_name,omitempty"` // AssumeRole 세션 이름 (미지정 시 기본값 사용)
+}
+
+// defaultSessionName은 Config.SessionName이 비어있을 때 사용하는 기본 세션 이름
+const defaultSessionName = "aws-inventory-tool"
+
+// LoadFromFile은 JSON 파일에서 계정 정보를 로드한다. regions가 지정되지 않은 계정은
+// AWS_REGION/profile 등 ambient AWS 설정이 해석하는 리전 하나로 채운다 (하드코딩된
+// 기본 리전으로 대체하면 ambient 설정에 의존하던 계정을 조용히 엉뚱한 리전으로 스캔하게 된다).
+func LoadFromFile(ctx context.Context, filename string) ([]Config, error) {
+	// 파일 읽기
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("파일 읽기 실패: %v", err)
+	}
+
+	// JSON 파싱
+	var configs []Config
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("JSON 파싱 실패: %v", err)
+	}
+
+	// regions 미지정 시 ambient 설정에서 리전을 해석해 채우기
+	for i := range configs {
+		if len(configs[i].Regions) == 0 {
+			region, err := resolveAmbientRegion(ctx, configs[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s: regions 미지정 시 기본 리전 해석 실패 (regions를 명시하세요): %v", configs[i].AccountID, err)
+			}
+			configs[i].Regions = []string{region}
+		}
+	}
+
+	return configs, nil
+}
+
+// resolveAmbientRegion은 regions가 지정되지 않은 계정에 대해 profile을 반영한 ambient AWS
+// 설정(AWS_REGION, ~/.aws/config 등)에서 리전을 해석한다.
+func resolveAmbientRegion(ctx context.Context, acc Config) (string, error) {
+	opts := []func(*config.LoadOptions) error{}
+	if acc.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(acc.Profile))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return "", fmt.Errorf("AWS 설정 로드 실패: %v", err)
+	}
+	if cfg.Region == "" {
+		return "", errors.New("AWS_REGION/profile에 리전이 설정되어 있지 않음")
+	}
+	return cfg.Region, nil
+}
+
+// LoadAWSConfig는 주어진 계정/리전에 대해 profile, AssumeRole, ExternalID, MFA를 반영한 aws.Config를 구성한다.
+func LoadAWSConfig(ctx context.Context, acc Config, region string) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+		config.WithRetryer(func() aws.Retryer {
+			return retry.AddWithMaxAttempts(retry.NewStandard(), maxRetryAttempts)
+		}),
+	}
+	if acc.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(acc.Profile))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("AWS 설정 로드 실패: %v", err)
+	}
+
+	// Role Assume (다른 계정 접근), ExternalID/MFA가 지정된 경우 함께 전달
+	stsClient := sts.NewFromConfig(cfg)
+	creds := stscreds.NewAssumeRoleProvider(stsClient, acc.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		if acc.ExternalID != "" {
+			o.ExternalID = &acc.ExternalID
+		}
+		o.RoleSessionName = acc.SessionName
+		if o.RoleSessionName == "" {
+			o.RoleSessionName = defaultSessionName
+		}
+		if acc.MfaSerial != "" {
+			o.SerialNumber = &acc.MfaSerial
+			o.TokenProvider = stscreds.StdinTokenProvider
+		}
+	})
+	cfg.Credentials = creds
+
+	return cfg, nil
+}
@@ -0,0 +1,168 @@
+// Package cache는 EC2 인벤토리를 SQLite(modernc.org/sqlite, cgo-free)에 보관해
+// 실행 간 diff(드리프트 감지)를 가능하게 한다.
+package cache
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/silverte/tools/internal/scanner"
+)
+
+// Event는 diff 결과에서 인스턴스가 어떻게 변했는지를 나타낸다.
+type Event string
+
+const (
+	EventAdded       Event = "added"
+	EventRemoved     Event = "removed"
+	EventChangedType Event = "changed-type"
+)
+
+// DiffRow는 diff 모드의 CSV/콘솔 출력 한 줄에 해당한다.
+type DiffRow struct {
+	AccountID    string
+	Region       string
+	InstanceID   string
+	TagName      string
+	InstanceType string
+	Event        Event
+}
+
+// Store는 EC2 인스턴스 캐시 DB에 대한 핸들이다.
+type Store struct {
+	db *sql.DB
+}
+
+// Open은 path의 SQLite DB를 열고 필요한 테이블을 생성한다.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("DB 열기 실패: %v", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS ec2_instances (
+	account_id    TEXT NOT NULL,
+	instance_id   TEXT NOT NULL,
+	region        TEXT,
+	tag_name      TEXT,
+	instance_type TEXT,
+	first_seen    INTEGER NOT NULL,
+	last_seen     INTEGER NOT NULL,
+	PRIMARY KEY (account_id, instance_id)
+);`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("스키마 생성 실패: %v", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close는 DB 연결을 닫는다.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// RecordAndDiff는 이번 조회 결과(instances, scanner.EC2Scanner가 만든 scanner.Instance)를
+// (account_id, instance_id) 기준으로 upsert하고, 직전 실행 대비 added/changed-type/removed 목록을 반환한다.
+// scannedAccounts는 이번 실행에서 실제로 (오류 없이) 조회를 마친 계정 ID 목록이며, removed 판정은
+// 이 목록에 속한 계정으로만 한정한다 — 계정 조회 자체가 타임아웃/오류로 실패해 instances에 전혀 안 잡힌
+// 경우까지 removed로 취급하면, 일시적 장애만으로 멀쩡한 인스턴스의 캐시 기록(first_seen 등)이
+// 사라지고 다음 실행에 added로 재등록되는 허위 added/removed 잡음이 생긴다.
+func (s *Store) RecordAndDiff(instances []scanner.Instance, scannedAccounts []string, now time.Time) ([]DiffRow, error) {
+	nowUnix := now.Unix()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("트랜잭션 시작 실패: %v", err)
+	}
+	defer tx.Rollback()
+
+	var diffs []DiffRow
+
+	for _, inst := range instances {
+		accountID, region, instanceID, tagName, instanceType := inst.AccountID, inst.Region, inst.InstanceID, inst.TagName, inst.InstanceType
+
+		var prevType string
+		err := tx.QueryRow(
+			`SELECT instance_type FROM ec2_instances WHERE account_id = ? AND instance_id = ?`,
+			accountID, instanceID,
+		).Scan(&prevType)
+
+		switch {
+		case err == sql.ErrNoRows:
+			if _, err := tx.Exec(
+				`INSERT INTO ec2_instances (account_id, instance_id, region, tag_name, instance_type, first_seen, last_seen)
+				 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				accountID, instanceID, region, tagName, instanceType, nowUnix, nowUnix,
+			); err != nil {
+				return nil, fmt.Errorf("insert 실패: %v", err)
+			}
+			diffs = append(diffs, DiffRow{accountID, region, instanceID, tagName, instanceType, EventAdded})
+
+		case err != nil:
+			return nil, fmt.Errorf("조회 실패: %v", err)
+
+		default:
+			if prevType != instanceType {
+				diffs = append(diffs, DiffRow{accountID, region, instanceID, tagName, instanceType, EventChangedType})
+			}
+			if _, err := tx.Exec(
+				`UPDATE ec2_instances SET region = ?, tag_name = ?, instance_type = ?, last_seen = ? WHERE account_id = ? AND instance_id = ?`,
+				region, tagName, instanceType, nowUnix, accountID, instanceID,
+			); err != nil {
+				return nil, fmt.Errorf("update 실패: %v", err)
+			}
+		}
+	}
+
+	if len(scannedAccounts) > 0 {
+		placeholders := make([]string, 0, len(scannedAccounts))
+		args := make([]any, 0, len(scannedAccounts)+1)
+		args = append(args, nowUnix)
+		for _, accountID := range scannedAccounts {
+			placeholders = append(placeholders, "?")
+			args = append(args, accountID)
+		}
+		accountFilter := strings.Join(placeholders, ", ")
+
+		removedRows, err := tx.Query(
+			`SELECT account_id, region, instance_id, tag_name, instance_type FROM ec2_instances
+			 WHERE last_seen < ? AND account_id IN (`+accountFilter+`)`,
+			args...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("removed 조회 실패: %v", err)
+		}
+		for removedRows.Next() {
+			var d DiffRow
+			if err := removedRows.Scan(&d.AccountID, &d.Region, &d.InstanceID, &d.TagName, &d.InstanceType); err != nil {
+				removedRows.Close()
+				return nil, fmt.Errorf("removed 스캔 실패: %v", err)
+			}
+			d.Event = EventRemoved
+			diffs = append(diffs, d)
+		}
+		removedRows.Close()
+
+		// removed로 보고한 행은 여기서 바로 지워, 다음 실행에서 같은 행이 다시 removed로
+		// 잡히지 않게 한다 (그렇지 않으면 한 번 사라진 인스턴스가 영영 removed로 반복 보고된다).
+		if _, err := tx.Exec(
+			`DELETE FROM ec2_instances WHERE last_seen < ? AND account_id IN (`+accountFilter+`)`,
+			args...,
+		); err != nil {
+			return nil, fmt.Errorf("removed 삭제 실패: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("커밋 실패: %v", err)
+	}
+
+	return diffs, nil
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/silverte/tools/internal/scanner"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(filepath.Join(t.TempDir(), "inventory.db"))
+	if err != nil {
+		t.Fatalf("Open() 실패: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func eventsOf(diffs []DiffRow) map[string]Event {
+	byInstance := make(map[string]Event, len(diffs))
+	for _, d := range diffs {
+		byInstance[d.InstanceID] = d.Event
+	}
+	return byInstance
+}
+
+// 계정 조회가 이번 실행에서 실패하면(scannedAccounts에서 빠지면) 그 계정의 인스턴스가 반드시
+// removed로 보고되지 않아야 한다 — 그렇지 않으면 일시적 장애가 드리프트로 오인된다.
+func TestRecordAndDiff_TransientAccountFailureDoesNotReportRemoved(t *testing.T) {
+	store := openTestStore(t)
+	base := time.Now()
+	instance := scanner.Instance{AccountID: "acct-1", Region: "us-east-1", InstanceID: "i-aaa", TagName: "web", InstanceType: "t3.micro"}
+
+	if _, err := store.RecordAndDiff([]scanner.Instance{instance}, []string{"acct-1"}, base); err != nil {
+		t.Fatalf("run1 RecordAndDiff() 실패: %v", err)
+	}
+
+	diffs, err := store.RecordAndDiff(nil, nil, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("run2 RecordAndDiff() 실패: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("계정 조회 실패 시 removed가 보고되지 않아야 하는데 %v를 받음", diffs)
+	}
+}
+
+// 계정이 정상적으로 조회되었는데 인스턴스가 더 이상 나타나지 않으면 removed로 한 번 보고되고,
+// 그 행은 삭제되어 이후 실행에서 같은 인스턴스가 다시 removed로 반복 보고되지 않아야 한다.
+func TestRecordAndDiff_GenuineRemovalReportedOnce(t *testing.T) {
+	store := openTestStore(t)
+	base := time.Now()
+	instance := scanner.Instance{AccountID: "acct-1", Region: "us-east-1", InstanceID: "i-aaa", TagName: "web", InstanceType: "t3.micro"}
+
+	if _, err := store.RecordAndDiff([]scanner.Instance{instance}, []string{"acct-1"}, base); err != nil {
+		t.Fatalf("run1 RecordAndDiff() 실패: %v", err)
+	}
+
+	diffs, err := store.RecordAndDiff(nil, []string{"acct-1"}, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("run2 RecordAndDiff() 실패: %v", err)
+	}
+	if got := eventsOf(diffs)["i-aaa"]; got != EventRemoved {
+		t.Fatalf("i-aaa가 removed로 보고되어야 하는데 이벤트 %q (diffs=%v)", got, diffs)
+	}
+
+	diffs, err = store.RecordAndDiff(nil, []string{"acct-1"}, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("run3 RecordAndDiff() 실패: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("이미 removed로 보고한 인스턴스가 다시 보고되지 않아야 하는데 %v를 받음", diffs)
+	}
+}
+
+// 장애로 한 번 누락되었다가 다음 실행에 다시 정상 조회되면, 여전히 떠 있는 인스턴스이므로
+// added가 아니라 별다른 diff 없이 조용히 갱신되어야 한다(허위 added/removed 잡음 없이).
+func TestRecordAndDiff_ReappearsAfterFailureRecoveryWithoutChurn(t *testing.T) {
+	store := openTestStore(t)
+	base := time.Now()
+	instance := scanner.Instance{AccountID: "acct-1", Region: "us-east-1", InstanceID: "i-aaa", TagName: "web", InstanceType: "t3.micro"}
+
+	if _, err := store.RecordAndDiff([]scanner.Instance{instance}, []string{"acct-1"}, base); err != nil {
+		t.Fatalf("run1 RecordAndDiff() 실패: %v", err)
+	}
+
+	// run2: 계정 조회가 실패해 instance가 빠짐 (장애)
+	if diffs, err := store.RecordAndDiff(nil, nil, base.Add(time.Hour)); err != nil {
+		t.Fatalf("run2 RecordAndDiff() 실패: %v", err)
+	} else if len(diffs) != 0 {
+		t.Fatalf("run2에서는 diff가 없어야 하는데 %v를 받음", diffs)
+	}
+
+	// run3: 같은 인스턴스가 다시 정상 조회됨 (장애 복구) -> added/removed 없이 조용히 갱신
+	diffs, err := store.RecordAndDiff([]scanner.Instance{instance}, []string{"acct-1"}, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("run3 RecordAndDiff() 실패: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("장애 복구 후 재등록이 added/removed 잡음을 만들면 안 되는데 %v를 받음", diffs)
+	}
+}
@@ -0,0 +1,72 @@
+// Package s3upload는 생성된 CSV 리포트를 S3 버킷에 업로드한다.
+package s3upload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Options는 업로드 대상과 보존 정책을 담는다.
+type Options struct {
+	Bucket     string
+	Prefix     string
+	Region     string
+	RetainDays int    // 0이면 lifecycle 태그를 붙이지 않음
+	KMSKeyID   string // 지정 시 SSE-KMS로 암호화, 미지정 시 SSE-S3(AES256)
+}
+
+// Upload은 localPath의 파일을 <prefix>/<basename 접두>_YYYY/MM/DD/HHMMSS.csv 키로 S3에 업로드한다.
+// 서버 측 암호화를 적용하되, KMSKeyID가 지정되면 해당 키로 SSE-KMS를, 그렇지 않으면 AES256(SSE-S3)을
+// 쓴다. RetainDays가 지정되면 버킷 lifecycle 규칙이 참조할 수 있는 "retain-days" 태그를 오브젝트에 붙인다.
+func Upload(ctx context.Context, localPath string, opts Options) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(opts.Region))
+	if err != nil {
+		return fmt.Errorf("AWS 설정 로드 실패: %v", err)
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("파일 열기 실패: %v", err)
+	}
+	defer file.Close()
+
+	now := time.Now()
+	base := filepath.Base(localPath)
+	key := fmt.Sprintf("%s/%s/%s.csv", opts.Prefix, now.Format("2006/01/02"), fmt.Sprintf("%s_%s", trimExt(base), now.Format("150405")))
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(opts.Bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}
+	if opts.KMSKeyID != "" {
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		input.SSEKMSKeyId = aws.String(opts.KMSKeyID)
+	} else {
+		input.ServerSideEncryption = types.ServerSideEncryptionAes256
+	}
+	if opts.RetainDays > 0 {
+		input.Tagging = aws.String("retain-days=" + strconv.Itoa(opts.RetainDays))
+	}
+
+	client := s3.NewFromConfig(cfg)
+	if _, err := client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("S3 업로드 실패: %v", err)
+	}
+
+	return nil
+}
+
+func trimExt(name string) string {
+	ext := filepath.Ext(name)
+	return name[:len(name)-len(ext)]
+}
@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// EBSScanner는 어떤 인스턴스에도 연결되지 않은(unattached) EBS 볼륨을 조회한다.
+type EBSScanner struct{}
+
+func (EBSScanner) Name() string { return "ebs" }
+
+func (EBSScanner) Headers() []string {
+	return []string{"Account ID", "Region", "Volume ID", "Size (GiB)", "Volume Type", "State"}
+}
+
+func (EBSScanner) Scan(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	client := ec2.NewFromConfig(awsCfg)
+
+	var rows []Row
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("status"), Values: []string{"available"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("페이지 조회 실패: %v", err)
+		}
+
+		for _, vol := range page.Volumes {
+			rows = append(rows, Row{
+				accountID,
+				region,
+				aws.ToString(vol.VolumeId),
+				strconv.Itoa(int(aws.ToInt32(vol.Size))),
+				string(vol.VolumeType),
+				string(vol.State),
+			})
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// EC2Scanner는 EC2 인스턴스를 조회한다.
+type EC2Scanner struct{}
+
+func (EC2Scanner) Name() string { return "ec2" }
+
+func (EC2Scanner) Headers() []string {
+	return []string{"Account ID", "Region", "Instance ID", "Tag Name", "Instance Type"}
+}
+
+// Instance는 EC2 인스턴스 조회 결과 한 건을 필드로 표현한다. Row()/ParseInstanceRow로
+// Scanner 공통 출력 형식(Row, Headers()와 순서가 일치하는 []string)과 서로 변환하되,
+// 그 변환을 이 파일 한 곳에만 두어 컬럼 순서가 바뀌면 여기서만 맞춰주면 되게 한다 — cache
+// 같은 호출자는 position이 아니라 named field로 값을 받는다.
+type Instance struct {
+	AccountID    string
+	Region       string
+	InstanceID   string
+	TagName      string
+	InstanceType string
+}
+
+// Row는 Instance를 Headers()와 순서가 일치하는 Row로 변환한다.
+func (i Instance) Row() Row {
+	return Row{i.AccountID, i.Region, i.InstanceID, i.TagName, i.InstanceType}
+}
+
+// ParseInstanceRow는 EC2Scanner.Scan이 만든 Row를 Instance로 되돌린다. 컬럼 수가 맞지 않으면
+// (예: Headers()가 바뀌었는데 호출자가 갱신되지 않은 경우) 조용히 잘못된 값을 채우는 대신 에러를 반환한다.
+func ParseInstanceRow(row Row) (Instance, error) {
+	if len(row) != 5 {
+		return Instance{}, fmt.Errorf("ec2 row 컬럼 수가 5가 아님: %d", len(row))
+	}
+	return Instance{
+		AccountID:    row[0],
+		Region:       row[1],
+		InstanceID:   row[2],
+		TagName:      row[3],
+		InstanceType: row[4],
+	}, nil
+}
+
+func (EC2Scanner) Scan(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	client := ec2.NewFromConfig(awsCfg)
+
+	var rows []Row
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("페이지 조회 실패: %v", err)
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				tagName := "-"
+				for _, tag := range instance.Tags {
+					if *tag.Key == "Name" {
+						tagName = *tag.Value
+						break
+					}
+				}
+
+				rows = append(rows, Instance{
+					AccountID:    accountID,
+					Region:       region,
+					InstanceID:   *instance.InstanceId,
+					TagName:      tagName,
+					InstanceType: string(instance.InstanceType),
+				}.Row())
+			}
+		}
+	}
+
+	return rows, nil
+}
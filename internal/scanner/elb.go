@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+// ELBScanner는 Classic ELB와 ALB/NLB(ELBv2)를 함께 조회한다.
+type ELBScanner struct{}
+
+func (ELBScanner) Name() string { return "elb" }
+
+func (ELBScanner) Headers() []string {
+	return []string{"Account ID", "Region", "Load Balancer Name", "Type", "DNS Name", "Scheme"}
+}
+
+func (ELBScanner) Scan(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	var rows []Row
+
+	classicRows, err := scanClassicELB(ctx, awsCfg, accountID, region)
+	if err != nil {
+		return nil, fmt.Errorf("classic ELB 조회 실패: %v", err)
+	}
+	rows = append(rows, classicRows...)
+
+	v2Rows, err := scanELBv2(ctx, awsCfg, accountID, region)
+	if err != nil {
+		return nil, fmt.Errorf("ALB/NLB 조회 실패: %v", err)
+	}
+	rows = append(rows, v2Rows...)
+
+	return rows, nil
+}
+
+func scanClassicELB(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	client := elasticloadbalancing.NewFromConfig(awsCfg)
+
+	var rows []Row
+	paginator := elasticloadbalancing.NewDescribeLoadBalancersPaginator(client, &elasticloadbalancing.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("페이지 조회 실패: %v", err)
+		}
+
+		for _, lb := range page.LoadBalancerDescriptions {
+			rows = append(rows, Row{
+				accountID,
+				region,
+				aws.ToString(lb.LoadBalancerName),
+				"classic",
+				aws.ToString(lb.DNSName),
+				aws.ToString(lb.Scheme),
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+func scanELBv2(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	client := elasticloadbalancingv2.NewFromConfig(awsCfg)
+
+	var rows []Row
+	paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(client, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("페이지 조회 실패: %v", err)
+		}
+
+		for _, lb := range page.LoadBalancers {
+			rows = append(rows, Row{
+				accountID,
+				region,
+				aws.ToString(lb.LoadBalancerName),
+				string(lb.Type),
+				aws.ToString(lb.DNSName),
+				string(lb.Scheme),
+			})
+		}
+	}
+
+	return rows, nil
+}
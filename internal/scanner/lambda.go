@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+// LambdaScanner는 Lambda 함수를 조회한다.
+type LambdaScanner struct{}
+
+func (LambdaScanner) Name() string { return "lambda" }
+
+func (LambdaScanner) Headers() []string {
+	return []string{"Account ID", "Region", "Function Name", "Runtime", "Memory (MB)", "Last Modified"}
+}
+
+func (LambdaScanner) Scan(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	client := lambda.NewFromConfig(awsCfg)
+
+	var rows []Row
+	paginator := lambda.NewListFunctionsPaginator(client, &lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("페이지 조회 실패: %v", err)
+		}
+
+		for _, fn := range page.Functions {
+			rows = append(rows, Row{
+				accountID,
+				region,
+				aws.ToString(fn.FunctionName),
+				string(fn.Runtime),
+				fmt.Sprintf("%d", aws.ToInt32(fn.MemorySize)),
+				aws.ToString(fn.LastModified),
+			})
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// RDSScanner는 RDS 인스턴스를 조회한다.
+type RDSScanner struct{}
+
+func (RDSScanner) Name() string { return "rds" }
+
+func (RDSScanner) Headers() []string {
+	return []string{"Account ID", "Region", "DB Instance ID", "Engine", "Instance Class", "Status"}
+}
+
+func (RDSScanner) Scan(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error) {
+	client := rds.NewFromConfig(awsCfg)
+
+	var rows []Row
+	paginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("페이지 조회 실패: %v", err)
+		}
+
+		for _, db := range page.DBInstances {
+			rows = append(rows, Row{
+				accountID,
+				region,
+				aws.ToString(db.DBInstanceIdentifier),
+				aws.ToString(db.Engine),
+				aws.ToString(db.DBInstanceClass),
+				aws.ToString(db.DBInstanceStatus),
+			})
+		}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,41 @@
+// Package scanner는 EC2/RDS/EBS/ELB/Lambda 등 AWS 리소스를 계정/리전 단위로 조회하는
+// 공통 인터페이스와 구현체를 제공한다.
+package scanner
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Row는 출력(콘솔 테이블/CSV) 한 줄에 해당하는 컬럼 값들이다. 순서는 Headers()와 일치해야 한다.
+type Row []string
+
+// Scanner는 단일 AWS 리소스 종류를 조회하는 방법을 정의한다.
+type Scanner interface {
+	// Name은 서브커맨드 이름이자 CSV 파일명에 쓰이는 리소스 식별자다 (예: "ec2", "rds").
+	Name() string
+	// Headers는 콘솔 테이블/CSV의 컬럼 헤더다.
+	Headers() []string
+	// Scan은 이미 자격 증명이 구성된 awsCfg로 accountID/region 하나를 조회해 Row 목록을 반환한다.
+	Scan(ctx context.Context, awsCfg aws.Config, accountID, region string) ([]Row, error)
+}
+
+// All은 CLI의 "all" 서브커맨드에서 실행할 전체 Scanner 목록이다.
+var All = []Scanner{
+	EC2Scanner{},
+	RDSScanner{},
+	EBSScanner{},
+	ELBScanner{},
+	LambdaScanner{},
+}
+
+// ByName은 서브커맨드 이름으로 Scanner를 찾는다.
+func ByName(name string) (Scanner, bool) {
+	for _, s := range All {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
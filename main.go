@@ -3,141 +3,425 @@ package main
 import (
 	"context"
 	"encoding/csv"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/cheggaaa/pb/v3"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+
+	"github.com/silverte/tools/internal/account"
+	"github.com/silverte/tools/internal/cache"
+	"github.com/silverte/tools/internal/s3upload"
+	"github.com/silverte/tools/internal/scanner"
 )
 
-// EC2Instance는 인스턴스 정보를 담는 구조체
-type EC2Instance struct {
-	AccountID    string
-	InstanceID   string
-	TagName      string
-	InstanceType string
+// maxConcurrentScans는 (account, region) 조합에 대해 동시에 실행할 goroutine 수의 상한
+const maxConcurrentScans = 10
+
+func main() {
+	app := &cli.App{
+		Name:  "aws-inventory",
+		Usage: "여러 AWS 계정의 리소스를 조회해 콘솔/CSV로 출력한다",
+		Commands: []*cli.Command{
+			ec2Command(),
+			scanCommand(scanner.RDSScanner{}),
+			scanCommand(scanner.EBSScanner{}),
+			scanCommand(scanner.ELBScanner{}),
+			scanCommand(scanner.LambdaScanner{}),
+			allCommand(),
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
 }
 
-// AccountConfig는 계정 설정 파일 구조
-type AccountConfig struct {
-	AccountID string `json:"account_id"`
-	RoleArn   string `json:"role_arn"`
+// scanFlags는 모든 스캔 서브커맨드("<subcommand> [flags] <accounts.json>" 형태)에
+// 공통으로 적용되는 플래그 목록을 만든다. cli.App이 아니라 각 cli.Command에 매달아야
+// "aws-inventory ec2 --silent accounts.json"처럼 서브커맨드 뒤에 오는 플래그가 인식된다.
+func scanFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{Name: "silent", Usage: "콘솔 출력을 모두 생략하고 CSV만 저장"},
+		&cli.BoolFlag{Name: "no-progress", Usage: "progress bar 표시 생략"},
+		&cli.StringFlag{Name: "s3-bucket", Usage: "CSV를 업로드할 S3 버킷 (미지정 시 업로드 생략)"},
+		&cli.StringFlag{Name: "s3-prefix", Usage: "S3 오브젝트 키 접두사"},
+		&cli.StringFlag{Name: "s3-region", Value: "us-east-1", Usage: "업로드에 사용할 S3 버킷의 리전"},
+		&cli.IntFlag{Name: "s3-retain-days", Usage: "지정 시 오브젝트에 retain-days 태그를 붙여 수명주기 정책이 정리할 수 있게 함"},
+		&cli.StringFlag{Name: "s3-kms-key-id", Usage: "지정 시 SSE-KMS로 암호화 (미지정 시 SSE-S3/AES256)"},
+		&cli.DurationFlag{Name: "per-account-timeout", Value: 60 * time.Second, Usage: "계정 하나를 조회하는 데 허용할 최대 시간"},
+		&cli.BoolFlag{Name: "ignore-errors", Usage: "일부 계정이 실패해도 0이 아닌 종료 코드를 반환하지 않음"},
+	}
 }
 
-// loadAccountsFromFile은 JSON 파일에서 계정 정보를 로드
-func loadAccountsFromFile(filename string) (map[string]string, error) {
-	// 파일 읽기
-	data, err := os.ReadFile(filename)
+// scanCommand는 Scanner 하나를 조회하는 서브커맨드를 만든다 (예: ec2, rds, ebs, elb, lambda).
+func scanCommand(s scanner.Scanner) *cli.Command {
+	return &cli.Command{
+		Name:      s.Name(),
+		Usage:     fmt.Sprintf("%s 리소스를 조회한다", s.Name()),
+		ArgsUsage: "<accounts.json>",
+		Flags:     scanFlags(),
+		Action: func(c *cli.Context) error {
+			return runScan(c, []scanner.Scanner{s})
+		},
+	}
+}
+
+// ec2Command는 ec2 서브커맨드를 만든다. 다른 Scanner와 달리 SQLite 캐시를 통한
+// 실행 간 diff(드리프트 감지)를 --cache/--diff 플래그로 지원한다.
+func ec2Command() *cli.Command {
+	s := scanner.EC2Scanner{}
+	return &cli.Command{
+		Name:      s.Name(),
+		Usage:     fmt.Sprintf("%s 리소스를 조회한다", s.Name()),
+		ArgsUsage: "<accounts.json>",
+		Flags: append(scanFlags(),
+			&cli.StringFlag{Name: "cache", Usage: "인벤토리를 누적 기록할 SQLite DB 경로"},
+			&cli.BoolFlag{Name: "diff", Usage: "이번 인벤토리 대신 직전 실행 대비 added/removed/changed-type만 출력 (--cache 필요)"},
+		),
+		Action: func(c *cli.Context) error {
+			return runScan(c, []scanner.Scanner{s})
+		},
+	}
+}
+
+// allCommand는 등록된 모든 Scanner를 순서대로 조회하는 "all" 서브커맨드를 만든다.
+func allCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "all",
+		Usage:     "모든 리소스 종류를 조회한다",
+		ArgsUsage: "<accounts.json>",
+		Flags:     scanFlags(),
+		Action: func(c *cli.Context) error {
+			return runScan(c, scanner.All)
+		},
+	}
+}
+
+// runScan은 accounts.json을 로드하고 scanners를 하나씩 순서대로 조회/출력/저장한다.
+func runScan(c *cli.Context, scanners []scanner.Scanner) error {
+	if c.NArg() < 1 {
+		return cli.Exit("사용법: aws-inventory <subcommand> [--silent] [--no-progress] <accounts.json>", 1)
+	}
+
+	silent := c.Bool("silent")
+	noProgress := c.Bool("no-progress")
+	ignoreErrors := c.Bool("ignore-errors")
+	perAccountTimeout := c.Duration("per-account-timeout")
+	inputFile := c.Args().First()
+
+	if silent {
+		log.SetOutput(io.Discard)
+	}
+
+	ctx := context.Background()
+
+	log.Printf("계정 정보 로드 중: %s", inputFile)
+	accounts, err := account.LoadFromFile(ctx, inputFile)
 	if err != nil {
-		return nil, fmt.Errorf("파일 읽기 실패: %v", err)
+		return fmt.Errorf("계정 정보 로드 실패: %v", err)
 	}
+	log.Printf("%d개 계정 로드 완료", len(accounts))
+
+	anyFailures := false
+	for _, s := range scanners {
+		rows, failures, err := scanAccounts(ctx, s, accounts, silent, noProgress, perAccountTimeout)
+		if err != nil {
+			return err
+		}
+
+		if len(failures) > 0 {
+			anyFailures = true
+			if !silent {
+				printFailureTable(s, failures)
+			}
+		}
+
+		timestamp := time.Now().Format("20060102_150405")
+
+		if s.Name() == "ec2" && c.String("cache") != "" {
+			scannedAccounts := succeededAccounts(accounts, failures)
+			diffRows, err := recordToCache(c.String("cache"), rows, scannedAccounts, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if c.Bool("diff") {
+				if !silent {
+					printDiffTable(diffRows)
+				}
 
-	// JSON 파싱
-	var accountConfigs []AccountConfig
-	if err := json.Unmarshal(data, &accountConfigs); err != nil {
-		return nil, fmt.Errorf("JSON 파싱 실패: %v", err)
+				diffFile := fmt.Sprintf("ec2_diff_%s.csv", timestamp)
+				log.Printf("diff CSV 파일 저장 중: %s", diffFile)
+				if err := writeDiffCSV(diffFile, diffRows); err != nil {
+					return fmt.Errorf("diff CSV 저장 실패: %v", err)
+				}
+				if !silent {
+					fmt.Printf("\n✓ diff CSV 파일 저장 완료: %s\n", diffFile)
+				}
+				continue
+			}
+		}
+
+		if !silent {
+			printTable(s, rows)
+		}
+
+		outputFile := fmt.Sprintf("%s_instances_%s.csv", s.Name(), timestamp)
+		log.Printf("CSV 파일 저장 중: %s", outputFile)
+		if err := writeCSV(outputFile, s.Headers(), rows); err != nil {
+			return fmt.Errorf("CSV 저장 실패: %v", err)
+		}
+
+		if !silent {
+			fmt.Printf("\n✓ CSV 파일 저장 완료: %s\n", outputFile)
+		}
+
+		if bucket := c.String("s3-bucket"); bucket != "" {
+			log.Printf("S3 업로드 중: s3://%s/%s", bucket, c.String("s3-prefix"))
+			err := s3upload.Upload(ctx, outputFile, s3upload.Options{
+				Bucket:     bucket,
+				Prefix:     c.String("s3-prefix"),
+				Region:     c.String("s3-region"),
+				RetainDays: c.Int("s3-retain-days"),
+				KMSKeyID:   c.String("s3-kms-key-id"),
+			})
+			if err != nil {
+				return fmt.Errorf("S3 업로드 실패: %v", err)
+			}
+			log.Printf("S3 업로드 완료: s3://%s/%s", bucket, c.String("s3-prefix"))
+		}
 	}
 
-	// map으로 변환
-	accounts := make(map[string]string)
-	for _, acc := range accountConfigs {
-		accounts[acc.AccountID] = acc.RoleArn
+	if anyFailures && !ignoreErrors {
+		return cli.Exit("일부 계정 조회에 실패했습니다 (--ignore-errors로 무시 가능)", 1)
 	}
 
-	return accounts, nil
+	return nil
+}
+
+// accountError는 계정 하나의 조회가 (일부라도) 실패했을 때의 결과를 담는다.
+type accountError struct {
+	AccountID string
+	Err       error
 }
 
-// getEC2Instances는 특정 account의 EC2 인스턴스를 조회
-func getEC2Instances(ctx context.Context, accountID, roleArn string, results chan<- []EC2Instance, wg *sync.WaitGroup) {
-	defer wg.Done()
+// scanAccounts는 주어진 Scanner에 대해 모든 (account, region) 조합을 조회한다.
+// region마다 goroutine을 fan out하되 sem으로 동시 실행 수를 제한해 API rate limit을 피하고,
+// 계정 하나당 perAccountTimeout으로 context를 제한한다. 실패한 계정은 results에서 누락되는 대신
+// accountError로 수집되어 호출자가 요약/종료 코드 판단에 쓸 수 있다.
+func scanAccounts(ctx context.Context, s scanner.Scanner, accounts []account.Config, silent, noProgress bool, perAccountTimeout time.Duration) ([]scanner.Row, []accountError, error) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentScans)
 
-	// AWS 기본 설정 로드
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		log.Printf("[%s] AWS 설정 로드 실패: %v", accountID, err)
-		results <- []EC2Instance{}
+	totalScans := 0
+	for _, acc := range accounts {
+		totalScans += len(acc.Regions)
+	}
+	results := make(chan []scanner.Row, totalScans)
+	failures := make(chan accountError, len(accounts))
+
+	// account 개수만큼 틱이 찍히는 progress bar (silent/no-progress 시 비활성화)
+	var bar *pb.ProgressBar
+	if !silent && !noProgress {
+		bar = pb.StartNew(len(accounts))
+	}
+
+	log.Printf("[%s] 조회 시작...", s.Name())
+	for _, acc := range accounts {
+		wg.Add(1)
+		go func(acc account.Config) {
+			defer wg.Done()
+			if bar != nil {
+				defer bar.Increment()
+			}
+
+			acctCtx, cancel := context.WithTimeout(ctx, perAccountTimeout)
+			defer cancel()
+
+			var regionWg sync.WaitGroup
+			var mu sync.Mutex
+			var regionErrs []error
+			for _, region := range acc.Regions {
+				regionWg.Add(1)
+				go func(region string) {
+					defer regionWg.Done()
+
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					awsCfg, err := account.LoadAWSConfig(acctCtx, acc, region)
+					if err != nil {
+						log.Printf("[%s/%s] %v", acc.AccountID, region, err)
+						mu.Lock()
+						regionErrs = append(regionErrs, fmt.Errorf("%s: %w", region, err))
+						mu.Unlock()
+						return
+					}
+
+					rows, err := s.Scan(acctCtx, awsCfg, acc.AccountID, region)
+					if err != nil {
+						log.Printf("[%s/%s] %s 조회 실패: %v", acc.AccountID, region, s.Name(), err)
+						mu.Lock()
+						regionErrs = append(regionErrs, fmt.Errorf("%s: %w", region, err))
+						mu.Unlock()
+						return
+					}
+
+					log.Printf("[%s/%s] %d개 %s 조회 완료", acc.AccountID, region, len(rows), s.Name())
+					results <- rows
+				}(region)
+			}
+			regionWg.Wait()
+
+			if len(regionErrs) > 0 {
+				failures <- accountError{AccountID: acc.AccountID, Err: errors.Join(regionErrs...)}
+			}
+		}(acc)
+	}
+
+	wg.Wait()
+	close(results)
+	close(failures)
+	if bar != nil {
+		bar.Finish()
+	}
+
+	var all []scanner.Row
+	for rows := range results {
+		all = append(all, rows...)
+	}
+
+	var failed []accountError
+	for f := range failures {
+		failed = append(failed, f)
+	}
+
+	return all, failed, nil
+}
+
+// printFailureTable은 실패한 계정 목록을 요약 테이블로 출력한다.
+func printFailureTable(s scanner.Scanner, failures []accountError) {
+	fmt.Println()
+	fmt.Printf("[%s] 조회에 실패한 계정 %d건:\n", s.Name(), len(failures))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Account ID", "Error"})
+	for _, f := range failures {
+		table.Append([]string{f.AccountID, f.Err.Error()})
+	}
+	table.Render()
+}
+
+// printTable은 tablewriter로 조회 결과를 콘솔에 출력한다.
+func printTable(s scanner.Scanner, rows []scanner.Row) {
+	fmt.Println()
+	if len(rows) == 0 {
+		fmt.Printf("조회된 %s 리소스가 없습니다.\n", s.Name())
 		return
 	}
 
-	// Role Assume (다른 계정 접근)
-	stsClient := sts.NewFromConfig(cfg)
-	creds := stscreds.NewAssumeRoleProvider(stsClient, roleArn)
-	cfg.Credentials = creds
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(s.Headers())
+	table.SetAutoWrapText(true)
+	for _, row := range rows {
+		table.Append([]string(row))
+	}
+	table.Render()
+
+	fmt.Printf("총 %d개 %s 리소스 조회 완료\n", len(rows), s.Name())
+}
+
+// recordToCache는 cachePath의 SQLite DB를 열어 rows(EC2Scanner.Scan이 만든 Row)를 typed
+// scanner.Instance로 변환해 upsert하고 직전 실행 대비 diff를 반환한다. scannedAccounts는
+// 이번 실행에서 오류 없이 조회를 마친 계정 ID 목록으로, removed 판정 범위를 제한하는 데
+// 쓰인다 (succeededAccounts 참고).
+func recordToCache(cachePath string, rows []scanner.Row, scannedAccounts []string, now time.Time) ([]cache.DiffRow, error) {
+	instances := make([]scanner.Instance, 0, len(rows))
+	for _, row := range rows {
+		inst, err := scanner.ParseInstanceRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("EC2 row 파싱 실패: %v", err)
+		}
+		instances = append(instances, inst)
+	}
 
-	// EC2 클라이언트 생성
-	client := ec2.NewFromConfig(cfg)
+	store, err := cache.Open(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("캐시 DB 열기 실패: %v", err)
+	}
+	defer store.Close()
 
-	// EC2 인스턴스 조회
-	result, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
+	diffRows, err := store.RecordAndDiff(instances, scannedAccounts, now)
 	if err != nil {
-		log.Printf("[%s] EC2 인스턴스 조회 실패: %v", accountID, err)
-		results <- []EC2Instance{}
-		return
+		return nil, fmt.Errorf("캐시 diff 실패: %v", err)
 	}
 
-	// 인스턴스 정보 수집
-	var instances []EC2Instance
-	for _, reservation := range result.Reservations {
-		for _, instance := range reservation.Instances {
-			instanceID := *instance.InstanceId
-			instanceType := string(instance.InstanceType)
+	return diffRows, nil
+}
 
-			// Name 태그 찾기
-			tagName := "-"
-			for _, tag := range instance.Tags {
-				if *tag.Key == "Name" {
-					tagName = *tag.Value
-					break
-				}
-			}
+// succeededAccounts는 accounts 중 failures에 없는, 즉 이번 실행에서 오류 없이 조회를
+// 마친 계정 ID 목록을 반환한다. 계정 조회가 통째로 실패하면 rows에 그 계정이 전혀
+// 안 잡히는데, 그런 계정까지 removed 판정에 포함시키면 일시적 장애를 드리프트로 오인한다.
+func succeededAccounts(accounts []account.Config, failures []accountError) []string {
+	failed := make(map[string]struct{}, len(failures))
+	for _, f := range failures {
+		failed[f.AccountID] = struct{}{}
+	}
 
-			instances = append(instances, EC2Instance{
-				AccountID:    accountID,
-				InstanceID:   instanceID,
-				TagName:      tagName,
-				InstanceType: instanceType,
-			})
+	succeeded := make([]string, 0, len(accounts))
+	for _, acc := range accounts {
+		if _, ok := failed[acc.AccountID]; !ok {
+			succeeded = append(succeeded, acc.AccountID)
 		}
 	}
 
-	log.Printf("[%s] %d개 인스턴스 조회 완료", accountID, len(instances))
-	results <- instances
+	return succeeded
 }
 
-// writeCSV는 결과를 CSV 파일로 저장
-func writeCSV(filename string, instances []EC2Instance) error {
-	// 파일 생성
+// printDiffTable은 diff 결과를 tablewriter로 콘솔에 출력한다.
+func printDiffTable(diffRows []cache.DiffRow) {
+	fmt.Println()
+	if len(diffRows) == 0 {
+		fmt.Println("직전 실행 대비 변경된 EC2 인스턴스가 없습니다.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Account ID", "Region", "Instance ID", "Tag Name", "Instance Type", "Event"})
+	table.SetAutoWrapText(true)
+	for _, d := range diffRows {
+		table.Append([]string{d.AccountID, d.Region, d.InstanceID, d.TagName, d.InstanceType, string(d.Event)})
+	}
+	table.Render()
+
+	fmt.Printf("총 %d건의 변경 사항\n", len(diffRows))
+}
+
+// writeDiffCSV는 diff 결과를 Event 컬럼이 포함된 CSV로 저장한다.
+func writeDiffCSV(filename string, diffRows []cache.DiffRow) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("파일 생성 실패: %v", err)
 	}
 	defer file.Close()
 
-	// CSV writer 생성
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
-	// 헤더 작성
-	header := []string{"Account ID", "Instance ID", "Tag Name", "Instance Type"}
+	header := []string{"Account ID", "Region", "Instance ID", "Tag Name", "Instance Type", "Event"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("헤더 작성 실패: %v", err)
 	}
 
-	// 데이터 작성
-	for _, instance := range instances {
-		record := []string{
-			instance.AccountID,
-			instance.InstanceID,
-			instance.TagName,
-			instance.InstanceType,
-		}
+	for _, d := range diffRows {
+		record := []string{d.AccountID, d.Region, d.InstanceID, d.TagName, d.InstanceType, string(d.Event)}
 		if err := writer.Write(record); err != nil {
 			return fmt.Errorf("데이터 작성 실패: %v", err)
 		}
@@ -146,74 +430,30 @@ func writeCSV(filename string, instances []EC2Instance) error {
 	return nil
 }
 
-func main() {
-	// 입력 파일명 확인
-	if len(os.Args) < 2 {
-		fmt.Println("사용법: go run main.go <accounts.json>")
-		fmt.Println("예시: go run main.go accounts.json")
-		os.Exit(1)
-	}
-
-	inputFile := os.Args[1]
-
-	// 계정 정보 로드
-	log.Printf("계정 정보 로드 중: %s", inputFile)
-	accounts, err := loadAccountsFromFile(inputFile)
+// writeCSV는 결과를 CSV 파일로 저장한다. header와 row의 컬럼 수/순서는 Scanner가 보장한다.
+func writeCSV(filename string, header []string, rows []scanner.Row) error {
+	// 파일 생성
+	file, err := os.Create(filename)
 	if err != nil {
-		log.Fatalf("계정 정보 로드 실패: %v", err)
+		return fmt.Errorf("파일 생성 실패: %v", err)
 	}
-	log.Printf("%d개 계정 로드 완료", len(accounts))
+	defer file.Close()
 
-	ctx := context.Background()
-	var wg sync.WaitGroup
-	results := make(chan []EC2Instance, len(accounts))
+	// CSV writer 생성
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
 
-	// 각 계정에 대해 goroutine으로 동시 조회
-	log.Println("EC2 인스턴스 조회 시작...")
-	for accountID, roleArn := range accounts {
-		wg.Add(1)
-		go getEC2Instances(ctx, accountID, roleArn, results, &wg)
+	// 헤더 작성
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("헤더 작성 실패: %v", err)
 	}
 
-	// 모든 goroutine 완료 대기
-	wg.Wait()
-	close(results)
-
-	// 결과 수집
-	var allInstances []EC2Instance
-	for instances := range results {
-		allInstances = append(allInstances, instances...)
-	}
-
-	// 콘솔 출력
-	fmt.Println("\n" + strings.Repeat("=", 95))
-	fmt.Printf("%-15s %-20s %-30s %-20s\n", "Account ID", "Instance ID", "Tag Name", "Instance Type")
-	fmt.Println(strings.Repeat("=", 95))
-
-	if len(allInstances) == 0 {
-		fmt.Println("조회된 EC2 인스턴스가 없습니다.")
-	} else {
-		for _, instance := range allInstances {
-			fmt.Printf("%-15s %-20s %-30s %-20s\n",
-				instance.AccountID,
-				instance.InstanceID,
-				instance.TagName,
-				instance.InstanceType,
-			)
+	// 데이터 작성
+	for _, row := range rows {
+		if err := writer.Write([]string(row)); err != nil {
+			return fmt.Errorf("데이터 작성 실패: %v", err)
 		}
 	}
 
-	fmt.Println(strings.Repeat("=", 95))
-	fmt.Printf("총 %d개 인스턴스 조회 완료\n", len(allInstances))
-
-	// CSV 파일 저장
-	timestamp := time.Now().Format("20060102_150405")
-	outputFile := fmt.Sprintf("ec2_instances_%s.csv", timestamp)
-
-	log.Printf("CSV 파일 저장 중: %s", outputFile)
-	if err := writeCSV(outputFile, allInstances); err != nil {
-		log.Fatalf("CSV 저장 실패: %v", err)
-	}
-
-	fmt.Printf("\n✓ CSV 파일 저장 완료: %s\n", outputFile)
-}
\ No newline at end of file
+	return nil
+}